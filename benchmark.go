@@ -0,0 +1,65 @@
+package tf
+
+import (
+	"reflect"
+	"testing"
+)
+
+// B wraps a func the same way F does, but runs it under a *testing.B so it
+// can be driven by Benchmark.
+type B struct {
+	b         *testing.B
+	fn        interface{}
+	args      []interface{}
+	fnArgsIn  []reflect.Type
+	fnArgsOut []reflect.Type
+}
+
+// Returns runs the wrapped function b.N times, with allocation reporting
+// enabled, and asserts that each call returns the expected tuple:
+//
+//	func BenchmarkRemainder(b *testing.B) {
+//	    Remainder := tf.Benchmark(b, Remainder)
+//
+//	    Remainder(10, 3).Returns(3, 1)
+//	}
+func (bf *B) Returns(expected ...interface{}) {
+	bf.b.Helper()
+	bf.b.ReportAllocs()
+
+	want := castReturns(expected, bf.fnArgsOut)
+
+	bf.b.ResetTimer()
+
+	for i := 0; i < bf.b.N; i++ {
+		actual := invoke(bf.fn, bf.fnArgsIn, bf.args)
+
+		bf.b.StopTimer()
+		for idx := range want {
+			if !reflect.DeepEqual(want[idx], actual[idx]) {
+				bf.b.Fatalf("unexpected return value at index %d: want %v, got %v", idx, want[idx], actual[idx])
+			}
+		}
+		bf.b.StartTimer()
+	}
+}
+
+// Benchmark wraps fn into B so it can be invoked under b.N the same way
+// Function wraps fn for a regular *testing.T:
+//
+//	func BenchmarkRemainder(b *testing.B) {
+//	    Remainder := tf.Benchmark(b, Remainder)
+//
+//	    Remainder(10, 3).Returns(3, 1)
+//	}
+func Benchmark(b *testing.B, fn interface{}) func(args ...interface{}) *B {
+	return func(args ...interface{}) *B {
+		return &B{
+			b:         b,
+			fn:        fn,
+			args:      args,
+			fnArgsIn:  getFunctionArgs(fn),
+			fnArgsOut: getFunctionReturns(fn),
+		}
+	}
+}
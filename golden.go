@@ -0,0 +1,153 @@
+package tf
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"testing"
+)
+
+// GoldenUpdate is true when the tests were run with -tf.update, which causes
+// ReturnsGolden and MatchesGolden to (re)write the golden file instead of
+// comparing against it.
+var GoldenUpdate bool
+
+func init() {
+	// Namespaced as tf.update, rather than the more common -update, so that
+	// registering this flag cannot panic with "flag redefined" if the test
+	// binary (or another imported package) already registers its own
+	// -update flag.
+	if flag.Lookup("tf.update") == nil {
+		flag.BoolVar(&GoldenUpdate, "tf.update", false, "update golden files")
+	}
+}
+
+type (
+	// GoldenMarshaler converts a value into the bytes that are stored in (and
+	// compared against) the golden file.
+	GoldenMarshaler func(v interface{}) ([]byte, error)
+
+	// GoldenOption configures ReturnsGolden and MatchesGolden.
+	GoldenOption func(*goldenOptions)
+
+	goldenOptions struct {
+		marshal GoldenMarshaler
+	}
+)
+
+func defaultGoldenOptions() *goldenOptions {
+	return &goldenOptions{
+		marshal: func(v interface{}) ([]byte, error) {
+			return json.MarshalIndent(v, "", "  ")
+		},
+	}
+}
+
+// GoldenMarshal overrides the default JSON marshaling used to produce the
+// golden file, for example to support YAML or raw bytes:
+//
+//	f.ReturnsGolden("testdata/foo.yaml", tf.GoldenMarshal(yaml.Marshal))
+func GoldenMarshal(m GoldenMarshaler) GoldenOption {
+	return func(o *goldenOptions) {
+		o.marshal = m
+	}
+}
+
+func checkGolden(t *testing.T, path string, v interface{}, options *goldenOptions) {
+	actualData, err := options.marshal(v)
+	if err != nil {
+		t.Errorf("tf: failed to marshal actual value: %s", err)
+		return
+	}
+
+	if GoldenUpdate {
+		if err := ioutil.WriteFile(path, actualData, 0644); err != nil {
+			t.Errorf("tf: failed to write golden file %s: %s", path, err)
+		}
+
+		return
+	}
+
+	goldenData, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Errorf("tf: failed to read golden file %s: %s (run with -tf.update to create it)", path, err)
+		return
+	}
+
+	if !bytes.Equal(bytes.TrimSpace(goldenData), bytes.TrimSpace(actualData)) {
+		t.Errorf("tf: golden file %s does not match:\n%s", path, diffLines(string(goldenData), string(actualData)))
+	}
+}
+
+// ReturnsGolden compares the function's return values against the contents
+// of path. Multiple return values are marshaled as a JSON array so they can
+// be stored in a single file. A single return value is marshaled on its own,
+// without being wrapped in an array.
+//
+// When the tests are run with -tf.update the golden file is (re)written with
+// the actual return values instead of being compared:
+//
+//	go test ./... -tf.update
+func (f *F) ReturnsGolden(path string) {
+	f.MatchesGolden(path)
+}
+
+// MatchesGolden is the same as ReturnsGolden, but accepts GoldenOption so a
+// different GoldenMarshaler can be used, for example to compare against
+// YAML, raw bytes or images instead of JSON:
+//
+//	Screenshot := tf.Function(t, Screenshot)
+//	Screenshot(page).MatchesGolden("testdata/screenshot.png", tf.GoldenMarshal(pngBytes))
+func (f *F) MatchesGolden(path string, opts ...GoldenOption) {
+	options := defaultGoldenOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	f.runFunc(func(t *testing.T, expected []interface{}, actual []interface{}) {
+		var v interface{} = actual
+		if len(actual) == 1 {
+			v = actual[0]
+		}
+
+		checkGolden(t, path, v, options)
+	})
+}
+
+// diffLines produces a line-by-line diff between two strings for use in
+// failure messages, rather than dumping the full contents of both files.
+// Lines are compared by position only, so an inserted or removed line marks
+// every line after it as changed.
+func diffLines(expected, actual string) string {
+	expectedLines := bytes.Split([]byte(expected), []byte("\n"))
+	actualLines := bytes.Split([]byte(actual), []byte("\n"))
+
+	max := len(expectedLines)
+	if len(actualLines) > max {
+		max = len(actualLines)
+	}
+
+	var buf bytes.Buffer
+	for i := 0; i < max; i++ {
+		var e, a []byte
+		if i < len(expectedLines) {
+			e = expectedLines[i]
+		}
+		if i < len(actualLines) {
+			a = actualLines[i]
+		}
+
+		if !bytes.Equal(e, a) {
+			if i < len(expectedLines) {
+				fmt.Fprintf(&buf, "-%s\n", e)
+			}
+			if i < len(actualLines) {
+				fmt.Fprintf(&buf, "+%s\n", a)
+			}
+		}
+	}
+
+	return buf.String()
+}
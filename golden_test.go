@@ -0,0 +1,27 @@
+package tf_test
+
+import (
+	"testing"
+
+	"github.com/elliotchance/tf"
+)
+
+func greeting(name string) string {
+	return "Hello, " + name + "!"
+}
+
+func TestReturnsGolden(t *testing.T) {
+	Greeting := tf.Function(t, greeting)
+
+	Greeting("World").ReturnsGolden("testdata/greeting.json")
+}
+
+func TestMatchesGolden(t *testing.T) {
+	Greeting := tf.Function(t, greeting)
+
+	raw := tf.GoldenMarshal(func(v interface{}) ([]byte, error) {
+		return []byte(v.(string)), nil
+	})
+
+	Greeting("World").MatchesGolden("testdata/greeting.txt", raw)
+}
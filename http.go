@@ -1,6 +1,7 @@
 package tf
 
 import (
+	"crypto/tls"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -16,6 +17,11 @@ type (
 	HTTPFinallyFunc func(request *http.Request, response *httptest.ResponseRecorder)
 	HTTPBeforeFunc  func(request *http.Request, response *httptest.ResponseRecorder)
 
+	// ResponseBodyMatcherFunc asserts something about body, returning false if
+	// it does not match. It is expected to report failures itself through t,
+	// the same as any other assertion.
+	ResponseBodyMatcherFunc func(t *testing.T, body []byte) bool
+
 	HTTPTester interface {
 		TestName() string
 		Tests() []*HTTPTest
@@ -59,9 +65,21 @@ type (
 		// equal.
 		//
 		// If you need to do more sophisticated checking or headers you should use
-		// Check.
+		// ResponseHeaderMatchers or Check.
 		ResponseHeaders map[string]string
 
+		// ResponseHeaderMatchers checks a header against a function instead of
+		// requiring an exact match, for example asserting that Content-Type
+		// starts with "application/json" without pinning the charset suffix:
+		//
+		//   ResponseHeaderMatchers: map[string]func(string) bool{
+		//       "Content-Type": func(v string) bool {
+		//           return strings.HasPrefix(v, "application/json")
+		//       },
+		//   }
+		//
+		ResponseHeaderMatchers map[string]func(string) bool
+
 		// ResponseBody will check the body of the response. ResponseBody must be
 		// not nil for the check to occur.
 		//
@@ -71,6 +89,12 @@ type (
 		//
 		ResponseBody io.Reader
 
+		// ResponseBodyMatcher checks the body of the response with a custom
+		// function instead of requiring an exact match. It takes precedence
+		// over ResponseBody if both are provided. See JSONEq, JSONPath, Regex
+		// and Contains for built-in matchers.
+		ResponseBodyMatcher ResponseBodyMatcherFunc
+
 		// Status is the expected response HTTP status code. You can use one of the
 		// constants in the http package such as http.StatusOK. If Status is not
 		// provided then the response status will not be checked.
@@ -92,6 +116,16 @@ type (
 		// Before is run after the request and record is setup but before the
 		// request is executed.
 		Before HTTPBeforeFunc
+
+		// HTTP2 makes the request look like it arrived over HTTP/2, so
+		// handlers that branch on r.ProtoMajor can be exercised without
+		// standing up a real TLS listener.
+		HTTP2 bool
+
+		// TLS, if not nil, is attached to the request as r.TLS so handlers
+		// that inspect the connection state (e.g. client certificates, SNI)
+		// can be tested.
+		TLS *tls.ConnectionState
 	}
 )
 
@@ -135,6 +169,16 @@ func testSingle(t *testing.T, test *HTTPTest, handlerFunc http.HandlerFunc) {
 	recorder := httptest.NewRecorder()
 	request := httptest.NewRequest(test.Method, test.RealPath(), test.RequestBody)
 
+	if test.HTTP2 {
+		request.Proto = "HTTP/2.0"
+		request.ProtoMajor = 2
+		request.ProtoMinor = 0
+	}
+
+	if test.TLS != nil {
+		request.TLS = test.TLS
+	}
+
 	defer func() {
 		if test.Finally != nil {
 			test.Finally(request, recorder)
@@ -167,7 +211,17 @@ func testSingle(t *testing.T, test *HTTPTest, handlerFunc http.HandlerFunc) {
 		}
 	}
 
-	if test.ResponseBody != nil {
+	for k, matches := range test.ResponseHeaderMatchers {
+		if !assert.Truef(t, matches(recorder.HeaderMap.Get(k)), "ResponseHeaderMatchers[%s]", k) {
+			return
+		}
+	}
+
+	if test.ResponseBodyMatcher != nil {
+		if !test.ResponseBodyMatcher(t, recorder.Body.Bytes()) {
+			return
+		}
+	} else if test.ResponseBody != nil {
 		expectedBody, err := ioutil.ReadAll(test.ResponseBody)
 		if !assert.NoError(t, err) {
 			return
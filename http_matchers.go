@@ -0,0 +1,71 @@
+package tf
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/PaesslerAG/jsonpath"
+	"github.com/stretchr/testify/assert"
+)
+
+// JSONEq returns a ResponseBodyMatcherFunc that asserts the response body is
+// structurally equal to expected once both are unmarshaled as JSON, ignoring
+// key order and whitespace differences:
+//
+//	ResponseBodyMatcher: tf.JSONEq(map[string]interface{}{"ok": true}),
+func JSONEq(expected interface{}) ResponseBodyMatcherFunc {
+	return func(t *testing.T, body []byte) bool {
+		expectedData, err := json.Marshal(expected)
+		if !assert.NoError(t, err) {
+			return false
+		}
+
+		return assert.JSONEq(t, string(expectedData), string(body))
+	}
+}
+
+// JSONPath returns a ResponseBodyMatcherFunc that evaluates path (in the
+// dotted/bracket notation used by github.com/PaesslerAG/jsonpath, e.g.
+// "$.users[0].name") against the JSON response body and asserts the result
+// equals expected:
+//
+//	ResponseBodyMatcher: tf.JSONPath("$.users[0].name", "Alice"),
+func JSONPath(path string, expected interface{}) ResponseBodyMatcherFunc {
+	return func(t *testing.T, body []byte) bool {
+		var v interface{}
+		if !assert.NoError(t, json.Unmarshal(body, &v)) {
+			return false
+		}
+
+		actual, err := jsonpath.Get(path, v)
+		if !assert.NoError(t, err) {
+			return false
+		}
+
+		return assert.Equal(t, expected, actual)
+	}
+}
+
+// Regex returns a ResponseBodyMatcherFunc that asserts the response body
+// matches pattern:
+//
+//	ResponseBodyMatcher: tf.Regex(`^\d+ items$`),
+func Regex(pattern string) ResponseBodyMatcherFunc {
+	re := regexp.MustCompile(pattern)
+
+	return func(t *testing.T, body []byte) bool {
+		return assert.Truef(t, re.Match(body), "response body %q does not match pattern %q", body, pattern)
+	}
+}
+
+// Contains returns a ResponseBodyMatcherFunc that asserts the response body
+// contains substr:
+//
+//	ResponseBodyMatcher: tf.Contains("Hello"),
+func Contains(substr string) ResponseBodyMatcherFunc {
+	return func(t *testing.T, body []byte) bool {
+		return assert.Truef(t, strings.Contains(string(body), substr), "response body %q does not contain %q", body, substr)
+	}
+}
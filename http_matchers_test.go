@@ -0,0 +1,37 @@
+package tf_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/elliotchance/tf"
+)
+
+func TestResponseBodyMatchers(t *testing.T) {
+	ServeHTTP := tf.ServeHTTP(t, tf.HTTPJSONResponse(http.StatusOK, map[string]interface{}{
+		"ok":   true,
+		"name": "Alice",
+	}))
+
+	ServeHTTP(&tf.MultiHTTPTest{
+		Name: "Matchers",
+		Steps: []*tf.HTTPTest{
+			{
+				Name:                "JSONEq",
+				ResponseBodyMatcher: tf.JSONEq(map[string]interface{}{"ok": true, "name": "Alice"}),
+			},
+			{
+				Name:                "JSONPath",
+				ResponseBodyMatcher: tf.JSONPath("$.name", "Alice"),
+			},
+			{
+				Name:                "Contains",
+				ResponseBodyMatcher: tf.Contains(`"name"`),
+			},
+			{
+				Name:                "Regex",
+				ResponseBodyMatcher: tf.Regex(`"ok":\s*true`),
+			},
+		},
+	})
+}
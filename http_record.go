@@ -0,0 +1,205 @@
+package tf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+)
+
+type (
+	// recordedInteraction is a single recorded (or replayed) request/response
+	// pair, keyed by method, path and a canonicalized request body so that
+	// JSON key ordering does not cause cache misses.
+	recordedInteraction struct {
+		Method      string            `json:"method"`
+		Path        string            `json:"path"`
+		RequestBody string            `json:"request_body"`
+		Status      int               `json:"status"`
+		Headers     map[string]string `json:"headers"`
+		Body        string            `json:"body"`
+
+		consumed bool
+	}
+
+	proxyRecording struct {
+		path         string
+		interactions []*recordedInteraction
+	}
+)
+
+// ReplayMode forces AddProxyHandler to serve every request from the cached
+// recording under testdata/ instead of reaching the network, regardless of
+// TF_RECORD. It defaults to false, in which case the TF_RECORD environment
+// variable is consulted on every request instead: TF_RECORD=0 means replay,
+// anything else (including unset) means record.
+var ReplayMode bool
+
+// replayMode reports whether AddProxyHandler should serve from the cached
+// recording for the current request. TF_RECORD is read lazily, rather than
+// once at package init, so that tests can toggle it with tf.SetEnv.
+func replayMode() bool {
+	return ReplayMode || os.Getenv("TF_RECORD") == "0"
+}
+
+func canonicalizeJSON(body []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return string(body)
+	}
+
+	return string(data)
+}
+
+func interactionKey(method, path, body string) string {
+	return method + " " + path + " " + body
+}
+
+func loadRecording(path string) *proxyRecording {
+	rec := &proxyRecording{path: path}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return rec
+	}
+
+	_ = json.Unmarshal(data, &rec.interactions)
+
+	return rec
+}
+
+func (r *proxyRecording) save() error {
+	sort.Slice(r.interactions, func(i, j int) bool {
+		return interactionKey(r.interactions[i].Method, r.interactions[i].Path, r.interactions[i].RequestBody) <
+			interactionKey(r.interactions[j].Method, r.interactions[j].Path, r.interactions[j].RequestBody)
+	})
+
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(r.path, data, 0644)
+}
+
+func (r *proxyRecording) find(method, path, body string) *recordedInteraction {
+	key := interactionKey(method, path, body)
+
+	for _, interaction := range r.interactions {
+		if interactionKey(interaction.Method, interaction.Path, interaction.RequestBody) == key {
+			return interaction
+		}
+	}
+
+	return nil
+}
+
+// AddProxyHandler registers a handler at path that records or replays
+// interactions with upstream. When recording (TF_RECORD is not "0" and
+// ReplayMode is false) every request is forwarded to upstream and the
+// (method, path, request-body) -> (status, headers, body) interaction is
+// appended to testdata/<name>.json; a request that is already in the file is
+// forwarded and appended again. When TF_RECORD=0 or ReplayMode is true, the
+// request is served from that file instead of reaching the network, and a
+// request with no recorded interaction gets a 404.
+//
+// This lets integration tests against third-party HTTP APIs degrade
+// gracefully to hermetic replays in CI. Call Verify() to assert that every
+// recorded interaction was consumed by the test.
+func (server *HTTPServer) AddProxyHandler(path, upstream string) *HTTPServer {
+	recordingPath := fmt.Sprintf("testdata/%s.json", safeTestName(strings.TrimPrefix(path, "/")))
+	recording := loadRecording(recordingPath)
+
+	if server.recordings == nil {
+		server.recordings = map[string]*proxyRecording{}
+	}
+	server.recordings[path] = recording
+
+	return server.AddHandler(path, func(w http.ResponseWriter, r *http.Request) {
+		requestBody, _ := ioutil.ReadAll(r.Body)
+		canonBody := canonicalizeJSON(requestBody)
+
+		if replayMode() {
+			interaction := recording.find(r.Method, r.URL.Path, canonBody)
+			if interaction == nil {
+				http.Error(w, fmt.Sprintf("tf: no recorded interaction for %s %s", r.Method, r.URL.Path), http.StatusNotFound)
+				return
+			}
+
+			interaction.consumed = true
+
+			for k, v := range interaction.Headers {
+				w.Header().Set(k, v)
+			}
+			w.WriteHeader(interaction.Status)
+			_, _ = w.Write([]byte(interaction.Body))
+
+			return
+		}
+
+		proxyReq, err := http.NewRequest(r.Method, upstream+r.URL.Path, bytes.NewReader(requestBody))
+		if err != nil {
+			panic(err)
+		}
+		proxyReq.Header = r.Header.Clone()
+
+		resp, err := http.DefaultClient.Do(proxyReq)
+		if err != nil {
+			panic(err)
+		}
+		defer resp.Body.Close()
+
+		responseBody, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			panic(err)
+		}
+
+		headers := map[string]string{}
+		for k := range resp.Header {
+			headers[k] = resp.Header.Get(k)
+		}
+
+		recording.interactions = append(recording.interactions, &recordedInteraction{
+			Method:      r.Method,
+			Path:        r.URL.Path,
+			RequestBody: canonBody,
+			Status:      resp.StatusCode,
+			Headers:     headers,
+			Body:        string(responseBody),
+			consumed:    true,
+		})
+
+		if err := recording.save(); err != nil {
+			panic(err)
+		}
+
+		for k, v := range headers {
+			w.Header().Set(k, v)
+		}
+		w.WriteHeader(resp.StatusCode)
+		_, _ = w.Write(responseBody)
+	})
+}
+
+// Verify asserts that every interaction recorded for this server's proxy
+// handlers was consumed during the test. It is a no-op if no proxy handlers
+// were added.
+func (server *HTTPServer) Verify(t *testing.T) {
+	for path, recording := range server.recordings {
+		for _, interaction := range recording.interactions {
+			if !interaction.consumed {
+				t.Errorf("tf: recorded interaction %s %s for %s was never replayed", interaction.Method, interaction.Path, path)
+			}
+		}
+	}
+}
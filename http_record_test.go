@@ -0,0 +1,131 @@
+package tf_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/elliotchance/tf"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddProxyHandler_Replay(t *testing.T) {
+	resetEnv := tf.SetEnv(t, "TF_RECORD", "0")
+	defer resetEnv()
+
+	server := tf.StartHTTPServer(0)
+	defer server.Shutdown()
+
+	server.AddProxyHandler("/users", "http://upstream.invalid")
+
+	resp, err := http.Get(fmt.Sprintf("http://localhost:%d/users", server.Port))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	server.Verify(t)
+}
+
+func TestAddProxyHandler_Record(t *testing.T) {
+	recordingPath := "testdata/orders.json"
+	defer os.Remove(recordingPath)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Upstream", "yes")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`[{"name":"Bob"}]`))
+	}))
+	defer upstream.Close()
+
+	server := tf.StartHTTPServer(0)
+	defer server.Shutdown()
+
+	server.AddProxyHandler("/orders", upstream.URL)
+
+	resp, err := http.Get(fmt.Sprintf("http://localhost:%d/orders", server.Port))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "yes", resp.Header.Get("X-Upstream"))
+	require.JSONEq(t, `[{"name":"Bob"}]`, string(body))
+	require.FileExists(t, recordingPath)
+
+	server.Verify(t)
+}
+
+func TestAddProxyHandler_CanonicalizesJSONBody(t *testing.T) {
+	recordingPath := "testdata/ordersSearch.json"
+	defer os.Remove(recordingPath)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer upstream.Close()
+
+	server := tf.StartHTTPServer(0)
+	defer server.Shutdown()
+	server.AddProxyHandler("/ordersSearch", upstream.URL)
+
+	resp, err := http.Post(
+		fmt.Sprintf("http://localhost:%d/ordersSearch", server.Port),
+		"application/json",
+		strings.NewReader(`{"name":"Alice","age":30}`),
+	)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	server.Verify(t)
+
+	resetEnv := tf.SetEnv(t, "TF_RECORD", "0")
+	defer resetEnv()
+
+	replayServer := tf.StartHTTPServer(0)
+	defer replayServer.Shutdown()
+	replayServer.AddProxyHandler("/ordersSearch", upstream.URL)
+
+	// The request body has the same keys as above, but in a different order,
+	// which should still hit the interaction recorded above.
+	resp2, err := http.Post(
+		fmt.Sprintf("http://localhost:%d/ordersSearch", replayServer.Port),
+		"application/json",
+		strings.NewReader(`{"age":30,"name":"Alice"}`),
+	)
+	require.NoError(t, err)
+	defer resp2.Body.Close()
+
+	body, err := ioutil.ReadAll(resp2.Body)
+	require.NoError(t, err)
+
+	require.Equal(t, http.StatusOK, resp2.StatusCode)
+	require.JSONEq(t, `{"ok":true}`, string(body))
+
+	replayServer.Verify(t)
+}
+
+func TestVerify_FailsWhenInteractionNotConsumed(t *testing.T) {
+	resetEnv := tf.SetEnv(t, "TF_RECORD", "0")
+	defer resetEnv()
+
+	server := tf.StartHTTPServer(0)
+	defer server.Shutdown()
+
+	server.AddProxyHandler("/users", "http://upstream.invalid")
+
+	// Deliberately never make a request, so the interaction recorded in
+	// testdata/users.json is never consumed.
+	fakeT := &testing.T{}
+	server.Verify(fakeT)
+
+	require.True(t, fakeT.Failed(), "expected Verify to fail an unconsumed interaction")
+}
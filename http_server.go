@@ -14,6 +14,10 @@ type HTTPServer struct {
 	Port     int
 	Shutdown func()
 	Mux      *http.ServeMux
+
+	// recordings tracks the recorded interactions for each path registered
+	// with AddProxyHandler, so Verify can assert they were all consumed.
+	recordings map[string]*proxyRecording
 }
 
 func (server *HTTPServer) Endpoint() string {
@@ -39,12 +43,14 @@ func (server *HTTPServer) AddHandlers(handlers map[string]http.HandlerFunc) *HTT
 	return server
 }
 
-func StartHTTPServer(port int) *HTTPServer {
-	mux := http.NewServeMux()
-	srv := &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: mux}
+// startServer starts srv listening on its Addr and runs serve (srv.Serve or
+// a TLS-serving equivalent) in the background, returning an HTTPServer whose
+// Port reflects the actual listening port (useful when Addr ends with ":0")
+// and whose Shutdown stops it. It is shared by StartHTTPServer and
+// startTLSServer.
+func startServer(mux *http.ServeMux, srv *http.Server, serve func(net.Listener) error) *HTTPServer {
 	server := &HTTPServer{
 		Mux:    mux,
-		Port:   port,
 		Server: srv,
 		Shutdown: func() {
 			// In some cases the shutdown will panic. We don't care about
@@ -57,9 +63,9 @@ func StartHTTPServer(port int) *HTTPServer {
 		},
 	}
 
-	// ListenAndServe() is not safe under test because it's possible the test
-	// will make a request before the listener is setup. So split them into
-	// their separate steps.
+	// ListenAndServe()/ListenAndServeTLS() is not safe under test because
+	// it's possible the test will make a request before the listener is
+	// setup. So split them into their separate steps.
 	listener, err := net.Listen("tcp", srv.Addr)
 	if err != nil {
 		panic(err)
@@ -69,11 +75,9 @@ func StartHTTPServer(port int) *HTTPServer {
 	server.Port, _ = strconv.Atoi(listeningOn[len(listeningOn)-1])
 
 	go func() {
-		// This will always return the error "http: Server closed" because the
-		// test explicitly closes it.
-		err := srv.Serve(listener)
-
-		if err != nil && err.Error() != "http: Server closed" {
+		// This will always return the error "http: Server closed" because
+		// the test explicitly closes it.
+		if err := serve(listener); err != nil && err.Error() != "http: Server closed" {
 			panic(err)
 		}
 	}()
@@ -81,6 +85,13 @@ func StartHTTPServer(port int) *HTTPServer {
 	return server
 }
 
+func StartHTTPServer(port int) *HTTPServer {
+	mux := http.NewServeMux()
+	srv := &http.Server{Addr: fmt.Sprintf(":%d", port), Handler: mux}
+
+	return startServer(mux, srv, srv.Serve)
+}
+
 func HTTPEmptyResponse(statusCode int) func(http.ResponseWriter, *http.Request) {
 	return HTTPStringResponse(statusCode, "")
 }
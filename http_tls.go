@@ -0,0 +1,98 @@
+package tf
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// GenerateSelfSignedCert creates a self-signed certificate and private key
+// for "localhost" and 127.0.0.1, suitable for use with StartHTTPSServer and
+// StartH2Server without having to ship PEM fixtures:
+//
+//	cert, key := tf.GenerateSelfSignedCert(t)
+//	server := tf.StartHTTPSServer(0, cert, key)
+func GenerateSelfSignedCert(t *testing.T) (certPEM, keyPEM []byte) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &priv.PublicKey, priv)
+	require.NoError(t, err)
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv)})
+
+	return certPEM, keyPEM
+}
+
+// startTLSServer is shared by StartHTTPSServer and StartH2Server. nextProtos
+// is used as the TLS config's NextProtos; srv.ServeTLS adds "http/1.1" to
+// the protocols offered during the TLS handshake if it is missing.
+func startTLSServer(port int, cert, key []byte, nextProtos []string) *HTTPServer {
+	tlsCert, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		panic(err)
+	}
+
+	mux := http.NewServeMux()
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: mux,
+		TLSConfig: &tls.Config{
+			Certificates: []tls.Certificate{tlsCert},
+			NextProtos:   nextProtos,
+		},
+	}
+
+	return startServer(mux, srv, func(listener net.Listener) error {
+		return srv.ServeTLS(listener, "", "")
+	})
+}
+
+// StartHTTPSServer is the same as StartHTTPServer but serves over TLS using
+// the provided PEM-encoded certificate and key. The connection negotiates
+// HTTP/2 with a fallback to HTTP/1.1, the same as a typical production
+// server:
+//
+//	cert, key := tf.GenerateSelfSignedCert(t)
+//	server := tf.StartHTTPSServer(0, cert, key)
+//	defer server.Shutdown()
+func StartHTTPSServer(port int, cert, key []byte) *HTTPServer {
+	return startTLSServer(port, cert, key, []string{"h2", "http/1.1"})
+}
+
+// StartH2Server is the same as StartHTTPSServer, but lists only "h2" in the
+// TLS config's NextProtos. HTTP/2 is not forced: srv.ServeTLS still offers
+// "http/1.1", so a client that does not offer "h2" is served over HTTP/1.1.
+// With an HTTP/2-capable client it can verify a handler behaves correctly
+// under HTTP/2 semantics, such as r.ProtoMajor == 2, trailers or server push
+// via http.Pusher:
+//
+//	cert, key := tf.GenerateSelfSignedCert(t)
+//	server := tf.StartH2Server(0, cert, key)
+//	defer server.Shutdown()
+func StartH2Server(port int, cert, key []byte) *HTTPServer {
+	return startTLSServer(port, cert, key, []string{"h2"})
+}
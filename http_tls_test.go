@@ -0,0 +1,85 @@
+package tf_test
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/elliotchance/tf"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+)
+
+func TestStartHTTPSServer(t *testing.T) {
+	cert, key := tf.GenerateSelfSignedCert(t)
+
+	server := tf.StartHTTPSServer(0, cert, key)
+	defer server.Shutdown()
+
+	server.AddHandler("/", tf.HTTPStringResponse(http.StatusOK, "secure"))
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+
+	resp, err := client.Get(fmt.Sprintf("https://localhost:%d/", server.Port))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestStartH2Server(t *testing.T) {
+	cert, key := tf.GenerateSelfSignedCert(t)
+
+	server := tf.StartH2Server(0, cert, key)
+	defer server.Shutdown()
+
+	var gotProtoMajor int
+	server.AddHandler("/", func(w http.ResponseWriter, r *http.Request) {
+		gotProtoMajor = r.ProtoMajor
+		w.WriteHeader(http.StatusOK)
+	})
+
+	// Setting TLSClientConfig explicitly disables Go's automatic HTTP/2
+	// upgrading, so it must be configured explicitly to get a transport that
+	// actually negotiates "h2" over ALPN.
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+	require.NoError(t, http2.ConfigureTransport(transport))
+
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(fmt.Sprintf("https://localhost:%d/", server.Port))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 2, gotProtoMajor)
+}
+
+func TestHTTPTest_HTTP2(t *testing.T) {
+	ServeHTTP := tf.ServeHTTP(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 {
+			http.Error(w, "expected HTTP/2", http.StatusExpectationFailed)
+			return
+		}
+
+		if r.TLS == nil {
+			http.Error(w, "expected a TLS connection state", http.StatusExpectationFailed)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	ServeHTTP(&tf.HTTPTest{
+		HTTP2:  true,
+		TLS:    &tls.ConnectionState{},
+		Status: http.StatusOK,
+	})
+}
@@ -0,0 +1,250 @@
+package tf
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type (
+	// Gen generates a random value of a specific type. It is used by Property
+	// to create randomized arguments for the wrapped function.
+	Gen func(rand *rand.Rand) interface{}
+
+	// PropertyFunc is the invariant checked against every generated input and
+	// output pair. It should return true if the property holds.
+	PropertyFunc func(in, out []interface{}) bool
+
+	// P wraps a func the same way F does, but drives it with randomly
+	// generated arguments instead of a single explicit case.
+	P struct {
+		t         *testing.T
+		fn        interface{}
+		fnArgsIn  []reflect.Type
+		fnArgsOut []reflect.Type
+		fnName    string
+	}
+)
+
+var (
+	generatorsMu sync.Mutex
+	generators   = map[reflect.Type]Gen{}
+)
+
+// RegisterGenerator associates a Gen with a type so Property can create
+// random values for arguments that are not covered by the built-in
+// generators (ints, floats, strings, slices and structs):
+//
+//	tf.RegisterGenerator(reflect.TypeOf(MyType{}), func(rand *rand.Rand) interface{} {
+//	    return MyType{N: rand.Int()}
+//	})
+func RegisterGenerator(t reflect.Type, gen Gen) {
+	generatorsMu.Lock()
+	generators[t] = gen
+	generatorsMu.Unlock()
+}
+
+// Property wraps fn into a P that can be driven with Holds, Idempotent or
+// Commutative:
+//
+//	ToUpper := tf.Property(t, strings.ToUpper)
+//	ToUpper.Idempotent(100)
+func Property(t *testing.T, fn interface{}) *P {
+	return &P{
+		t:         t,
+		fn:        fn,
+		fnArgsIn:  getFunctionArgs(fn),
+		fnArgsOut: getFunctionReturns(fn),
+		fnName:    getFunctionName(fn),
+	}
+}
+
+func genFor(t reflect.Type) Gen {
+	generatorsMu.Lock()
+	gen, ok := generators[t]
+	generatorsMu.Unlock()
+
+	if ok {
+		return gen
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(r *rand.Rand) interface{} {
+			return reflect.ValueOf(r.Int63() - r.Int63()).Convert(t).Interface()
+		}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(r *rand.Rand) interface{} {
+			return reflect.ValueOf(r.Uint64()).Convert(t).Interface()
+		}
+
+	case reflect.Float32, reflect.Float64:
+		return func(r *rand.Rand) interface{} {
+			return reflect.ValueOf(r.NormFloat64()).Convert(t).Interface()
+		}
+
+	case reflect.String:
+		return func(r *rand.Rand) interface{} {
+			n := r.Intn(16)
+			b := make([]byte, n)
+			for i := range b {
+				b[i] = byte('a' + r.Intn(26))
+			}
+
+			return reflect.ValueOf(string(b)).Convert(t).Interface()
+		}
+
+	case reflect.Slice:
+		elemGen := genFor(t.Elem())
+
+		return func(r *rand.Rand) interface{} {
+			n := r.Intn(8)
+			s := reflect.MakeSlice(t, n, n)
+			for i := 0; i < n; i++ {
+				s.Index(i).Set(reflect.ValueOf(elemGen(r)))
+			}
+
+			return s.Interface()
+		}
+
+	case reflect.Struct:
+		return func(r *rand.Rand) interface{} {
+			v := reflect.New(t).Elem()
+			for i := 0; i < t.NumField(); i++ {
+				if !v.Field(i).CanSet() {
+					continue
+				}
+
+				v.Field(i).Set(reflect.ValueOf(genFor(t.Field(i).Type)(r)))
+			}
+
+			return v.Interface()
+		}
+
+	default:
+		panic(fmt.Sprintf("tf: no default generator for type %s, use tf.RegisterGenerator", t))
+	}
+}
+
+func (p *P) generate(r *rand.Rand) []interface{} {
+	args := make([]interface{}, len(p.fnArgsIn))
+	for i, t := range p.fnArgsIn {
+		args[i] = genFor(t)(r)
+	}
+
+	return args
+}
+
+func (p *P) invoke(args []interface{}) []interface{} {
+	return invoke(p.fn, p.fnArgsIn, args)
+}
+
+// shrink repeatedly tries to find a smaller failing input by halving numeric
+// arguments towards zero and bisecting the length of strings and slices. It
+// stops as soon as shrinking no longer produces a failure.
+func (p *P) shrink(args []interface{}, holds PropertyFunc) []interface{} {
+	for {
+		smaller, ok := shrinkOnce(args)
+		if !ok {
+			return args
+		}
+
+		if holds(smaller, p.invoke(smaller)) {
+			return args
+		}
+
+		args = smaller
+	}
+}
+
+func shrinkOnce(args []interface{}) ([]interface{}, bool) {
+	smaller := make([]interface{}, len(args))
+	copy(smaller, args)
+	shrunkAny := false
+
+	for i, arg := range args {
+		v := reflect.ValueOf(arg)
+
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if n := v.Int(); n != 0 {
+				smaller[i] = reflect.ValueOf(n / 2).Convert(v.Type()).Interface()
+				shrunkAny = true
+			}
+
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if n := v.Uint(); n != 0 {
+				smaller[i] = reflect.ValueOf(n / 2).Convert(v.Type()).Interface()
+				shrunkAny = true
+			}
+
+		case reflect.String:
+			if s := v.String(); len(s) > 0 {
+				smaller[i] = s[:len(s)/2]
+				shrunkAny = true
+			}
+
+		case reflect.Slice:
+			if n := v.Len(); n > 0 {
+				smaller[i] = v.Slice(0, n/2).Interface()
+				shrunkAny = true
+			}
+		}
+	}
+
+	return smaller, shrunkAny
+}
+
+// Holds generates n random input tuples and asserts that fn(in, out) returns
+// true for all of them. If a failing case is found it is shrunk to a minimal
+// reproduction and reported with t.Errorf along with the seed that was used,
+// so the failure can be reproduced.
+func (p *P) Holds(n int, holds PropertyFunc) {
+	seed := time.Now().UnixNano()
+	p.HoldsWithSeed(n, seed, holds)
+}
+
+// HoldsWithSeed is the same as Holds but uses an explicit seed, which is
+// useful for reproducing a previously reported failure.
+func (p *P) HoldsWithSeed(n int, seed int64, holds PropertyFunc) {
+	r := rand.New(rand.NewSource(seed))
+
+	for i := 0; i < n; i++ {
+		args := p.generate(r)
+		out := p.invoke(args)
+
+		if !holds(args, out) {
+			minimal := p.shrink(args, holds)
+			p.t.Errorf("%s: property failed for input %v (seed %d)", p.fnName, minimal, seed)
+			return
+		}
+	}
+}
+
+// Idempotent asserts that calling fn twice in a row with the same argument
+// (feeding the first return value back in) produces the same result as
+// calling it once. fn must have exactly one argument and one return value of
+// the same type.
+func (p *P) Idempotent(n int) {
+	p.Holds(n, func(in, out []interface{}) bool {
+		again := p.invoke(out)
+
+		return reflect.DeepEqual(out, again)
+	})
+}
+
+// Commutative asserts that swapping fn's first two arguments does not change
+// the result. fn must have at least two arguments of the same type.
+func (p *P) Commutative(n int) {
+	p.Holds(n, func(in, out []interface{}) bool {
+		swapped := make([]interface{}, len(in))
+		copy(swapped, in)
+		swapped[0], swapped[1] = swapped[1], swapped[0]
+
+		return reflect.DeepEqual(out, p.invoke(swapped))
+	})
+}
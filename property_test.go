@@ -0,0 +1,74 @@
+package tf_test
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/elliotchance/tf"
+)
+
+func TestProperty_Idempotent(t *testing.T) {
+	ToUpper := tf.Property(t, strings.ToUpper)
+
+	ToUpper.Idempotent(50)
+}
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+
+	return a
+}
+
+func TestProperty_Holds(t *testing.T) {
+	Abs := tf.Property(t, abs)
+
+	Abs.Holds(50, func(in, out []interface{}) bool {
+		return out[0].(int) >= 0
+	})
+}
+
+func addInts(a, b int) int {
+	return a + b
+}
+
+func TestProperty_Commutative(t *testing.T) {
+	Add := tf.Property(t, addInts)
+
+	Add.Commutative(50)
+}
+
+type customGenType struct {
+	N int
+}
+
+// TestRegisterGenerator_Race exercises RegisterGenerator and Property(...).
+// Holds concurrently, so that `go test -race` can catch the generators
+// registry being accessed without synchronization.
+func TestRegisterGenerator_Race(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+
+		tf.RegisterGenerator(reflect.TypeOf(customGenType{}), func(r *rand.Rand) interface{} {
+			return customGenType{N: r.Int()}
+		})
+	}()
+
+	go func() {
+		defer wg.Done()
+
+		Abs := tf.Property(t, abs)
+		Abs.Holds(20, func(in, out []interface{}) bool {
+			return out[0].(int) >= 0
+		})
+	}()
+
+	wg.Wait()
+}
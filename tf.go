@@ -41,6 +41,7 @@ import (
 	"reflect"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -55,13 +56,15 @@ type (
 		fnArgsIn  []reflect.Type
 		fnArgsOut []reflect.Type
 		fnName    string
+		parallel  bool
 	}
 
 	handleFunc func(t *testing.T, expected []interface{}, actual []interface{})
 )
 
 var (
-	funcMap = map[string]int{}
+	funcMapMu sync.Mutex
+	funcMap   = map[string]int{}
 )
 
 // Returns matches if expected result matches actual
@@ -127,37 +130,66 @@ func (f *F) Errors(args ...interface{}) {
 	})
 }
 
-func (f *F) runFunc(h handleFunc, expected ...interface{}) {
-	if _, ok := funcMap[f.fnName]; !ok {
-		funcMap[f.fnName] = 0
+// castArgs converts args into reflect.Values matching types, treating a nil
+// argument as the zero value for that type.
+func castArgs(args []interface{}, types []reflect.Type) []reflect.Value {
+	values := make([]reflect.Value, len(args))
+	for idx, arg := range args {
+		if arg == nil {
+			values[idx] = reflect.Zero(types[idx])
+		} else {
+			values[idx] = reflect.ValueOf(arg).Convert(types[idx])
+		}
 	}
 
-	funcMap[f.fnName]++
+	return values
+}
 
-	f.t.Run(fmt.Sprintf("%s#%d", f.fnName, funcMap[f.fnName]), func(t *testing.T) {
-		// Casting calling arguments
-		argsIn := make([]reflect.Value, len(f.args))
-		for idx, arg := range f.args {
-			if arg == nil {
-				argsIn[idx] = reflect.Zero(f.fnArgsIn[idx])
-			} else {
-				argsIn[idx] = reflect.ValueOf(arg).Convert(f.fnArgsIn[idx])
-			}
+// castReturns converts expected into concrete values matching types, the
+// same way castArgs does for arguments. It is used to normalize an expected
+// tuple (e.g. nil, or a differently-sized int type) before comparing it
+// against actual return values.
+func castReturns(expected []interface{}, types []reflect.Type) []interface{} {
+	out := make([]interface{}, len(expected))
+	for idx, e := range expected {
+		if e == nil {
+			out[idx] = reflect.Zero(types[idx]).Interface()
+		} else {
+			out[idx] = reflect.ValueOf(e).Convert(types[idx]).Interface()
 		}
+	}
 
-		returns := make([]interface{}, len(f.fnArgsOut))
-		for idx, r := range reflect.ValueOf(f.fn).Call(argsIn) {
-			returns[idx] = r.Interface()
-		}
+	return out
+}
+
+// invoke calls fn with args (cast via castArgs against fnArgsIn) and returns
+// its results as []interface{}.
+func invoke(fn interface{}, fnArgsIn []reflect.Type, args []interface{}) []interface{} {
+	argsIn := castArgs(args, fnArgsIn)
+
+	results := reflect.ValueOf(fn).Call(argsIn)
+	out := make([]interface{}, len(results))
+	for idx, r := range results {
+		out[idx] = r.Interface()
+	}
+
+	return out
+}
+
+func (f *F) runFunc(h handleFunc, expected ...interface{}) {
+	funcMapMu.Lock()
+	funcMap[f.fnName]++
+	n := funcMap[f.fnName]
+	funcMapMu.Unlock()
 
-		for idx, e := range expected {
-			if e == nil {
-				expected[idx] = reflect.Zero(f.fnArgsOut[idx]).Interface()
-			} else {
-				expected[idx] = reflect.ValueOf(e).Convert(f.fnArgsOut[idx]).Interface()
-			}
+	f.t.Run(fmt.Sprintf("%s#%d", f.fnName, n), func(t *testing.T) {
+		if f.parallel {
+			t.Parallel()
 		}
 
+		returns := invoke(f.fn, f.fnArgsIn, f.args)
+		expected = castReturns(expected, f.fnArgsOut)
+
 		h(t, expected, returns)
 	})
 }
@@ -194,6 +226,42 @@ func (f *F) False() {
 	f.Returns(false)
 }
 
+// Parallel marks the generated subtest as safe to run in parallel with other
+// parallel tests, the same as calling t.Parallel() directly:
+//
+//	Remainder := tf.Function(t, Remainder)
+//	Remainder(10, 3).Parallel().Returns(3, 1)
+//
+func (f *F) Parallel() *F {
+	f.parallel = true
+
+	return f
+}
+
+// Race invokes the wrapped function n times concurrently and asserts that
+// every call returns the same tuple as expected. This is useful for
+// validating the thread-safety of a pure-ish function.
+func (f *F) Race(n int, expected ...interface{}) {
+	f.t.Run(fmt.Sprintf("%s/Race", f.fnName), func(t *testing.T) {
+		want := castReturns(expected, f.fnArgsOut)
+
+		var wg sync.WaitGroup
+		wg.Add(n)
+
+		for i := 0; i < n; i++ {
+			go func() {
+				defer wg.Done()
+
+				actual := invoke(f.fn, f.fnArgsIn, f.args)
+
+				assert.Equal(t, want, actual)
+			}()
+		}
+
+		wg.Wait()
+	})
+}
+
 func getFunctionName(fn interface{}) string {
 	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
 	parts := strings.Split(name, ".")
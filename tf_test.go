@@ -128,3 +128,22 @@ func TestTrueFalse(t *testing.T) {
 	Booler(true).True()
 	Booler(false).False()
 }
+
+func TestRemainder_Parallel(t *testing.T) {
+	Remainder := tf.Function(t, Remainder)
+
+	Remainder(10, 3).Parallel().Returns(3, 1)
+	Remainder(10, 2).Parallel().Returns(5, 0)
+}
+
+func TestRemainder_Race(t *testing.T) {
+	Remainder := tf.Function(t, Remainder)
+
+	Remainder(10, 3).Race(50, 3, 1)
+}
+
+func BenchmarkRemainder(b *testing.B) {
+	Remainder := tf.Benchmark(b, Remainder)
+
+	Remainder(10, 3).Returns(3, 1)
+}
@@ -0,0 +1,178 @@
+package tf
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+type (
+	// Frame is a single websocket message. Send and Expect also accept a
+	// string (a text frame) or a []byte (a binary frame) in place of a Frame.
+	Frame struct {
+		// Type is one of websocket.TextMessage or websocket.BinaryMessage.
+		Type int
+
+		// Data is the frame payload.
+		Data []byte
+	}
+
+	// WebsocketStep describes a single interaction with the server: send a
+	// frame, then (optionally) expect one back.
+	WebsocketStep struct {
+		// Send is the frame to send. It may be a string, []byte or Frame. If
+		// nil, nothing is sent for this step.
+		Send interface{}
+
+		// Expect is the frame expected back. It may be a string, []byte or
+		// Frame. If nil, no frame is read for this step.
+		Expect interface{}
+
+		// ExpectCloseCode, if not zero, asserts that the connection was
+		// closed by the server with this close code instead of a regular
+		// frame being returned.
+		ExpectCloseCode int
+
+		// Timeout bounds how long to wait for Expect or ExpectCloseCode. If
+		// zero, DefaultWebsocketTimeout is used.
+		Timeout time.Duration
+	}
+
+	// WebsocketTest scripts a sequence of Steps against an http.Handler that
+	// upgrades the connection to a websocket.
+	WebsocketTest struct {
+		// Name is used as the test name. If it is empty the test name will
+		// be based on the Path.
+		Name string
+
+		// Path used to dial the server. If blank, "/" is used.
+		Path string
+
+		// Steps are run in order against the same connection.
+		Steps []*WebsocketStep
+
+		// Before is run after the connection is dialed but before any Steps
+		// run.
+		Before func(conn *websocket.Conn)
+
+		// Finally is called as the last event once the connection has been
+		// dialed, even if a step fails, so the connection can be cleaned up.
+		// It is not called if dialing fails.
+		Finally func(conn *websocket.Conn)
+	}
+)
+
+// DefaultWebsocketTimeout is used for a WebsocketStep when Timeout is zero.
+var DefaultWebsocketTimeout = time.Second
+
+func frameFor(v interface{}) Frame {
+	switch f := v.(type) {
+	case Frame:
+		return f
+	case string:
+		return Frame{Type: websocket.TextMessage, Data: []byte(f)}
+	case []byte:
+		return Frame{Type: websocket.BinaryMessage, Data: f}
+	default:
+		panic("tf: Send/Expect must be a string, []byte or tf.Frame")
+	}
+}
+
+func (wt *WebsocketTest) realPath() string {
+	if wt.Path == "" {
+		return "/"
+	}
+
+	return wt.Path
+}
+
+func (wt *WebsocketTest) testName() string {
+	if wt.Name != "" {
+		return wt.Name
+	}
+
+	return "WS " + wt.realPath()
+}
+
+// ServeWebsocket runs a WebsocketTest against handler. It starts an
+// httptest.Server for the handler, dials it as a websocket client and plays
+// through wt.Steps in order, asserting frames as they are declared.
+func ServeWebsocket(t *testing.T, handler http.Handler) func(wt *WebsocketTest) {
+	return func(wt *WebsocketTest) {
+		t.Run(safeTestName(wt.testName()), func(t *testing.T) {
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			url := "ws" + strings.TrimPrefix(server.URL, "http") + wt.realPath()
+
+			conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			defer func() {
+				if wt.Finally != nil {
+					wt.Finally(conn)
+				}
+
+				conn.Close()
+			}()
+
+			if wt.Before != nil {
+				wt.Before(conn)
+			}
+
+			for _, step := range wt.Steps {
+				if !runWebsocketStep(t, conn, step) {
+					return
+				}
+			}
+		})
+	}
+}
+
+func runWebsocketStep(t *testing.T, conn *websocket.Conn, step *WebsocketStep) bool {
+	if step.Send != nil {
+		send := frameFor(step.Send)
+		if !assert.NoError(t, conn.WriteMessage(send.Type, send.Data)) {
+			return false
+		}
+	}
+
+	if step.Expect == nil && step.ExpectCloseCode == 0 {
+		return true
+	}
+
+	timeout := step.Timeout
+	if timeout == 0 {
+		timeout = DefaultWebsocketTimeout
+	}
+
+	if !assert.NoError(t, conn.SetReadDeadline(time.Now().Add(timeout))) {
+		return false
+	}
+
+	msgType, data, err := conn.ReadMessage()
+	if step.ExpectCloseCode != 0 {
+		closeErr, ok := err.(*websocket.CloseError)
+		if !assert.True(t, ok, "expected a close frame") {
+			return false
+		}
+
+		return assert.Equal(t, step.ExpectCloseCode, closeErr.Code)
+	}
+
+	if !assert.NoError(t, err) {
+		return false
+	}
+
+	expect := frameFor(step.Expect)
+
+	return assert.Equal(t, expect.Type, msgType) &&
+		assert.Equal(t, string(expect.Data), string(data))
+}
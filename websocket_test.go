@@ -0,0 +1,42 @@
+package tf_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/elliotchance/tf"
+	"github.com/gorilla/websocket"
+)
+
+var echoUpgrader = websocket.Upgrader{}
+
+func echoWebsocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := echoUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	for {
+		msgType, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		if err := conn.WriteMessage(msgType, data); err != nil {
+			return
+		}
+	}
+}
+
+func TestServeWebsocket(t *testing.T) {
+	ServeWebsocket := tf.ServeWebsocket(t, http.HandlerFunc(echoWebsocket))
+
+	ServeWebsocket(&tf.WebsocketTest{
+		Name: "Echo",
+		Steps: []*tf.WebsocketStep{
+			{Send: "hello", Expect: "hello"},
+			{Send: "world", Expect: "world"},
+		},
+	})
+}